@@ -0,0 +1,29 @@
+// Package backends defines the pluggable output format bdog renders an
+// introspected schema.Schema into. The SDK backend (backends/sdk) emits
+// a standalone Go CRUD SDK; the REST backend (backends/rest) emits a
+// net/http JSON API exposing the same schema. Both are selected by
+// main() via the -backend flag.
+package backends
+
+import (
+	"io"
+	"time"
+
+	"github.com/pbnjay/bdog/schema"
+)
+
+// Backend turns an introspected Schema into generated Go source,
+// written to w.
+type Backend interface {
+	Generate(s *schema.Schema, w io.Writer) error
+}
+
+// Meta carries the run-level information every Backend needs alongside
+// the Schema itself: who/when generated this, what package it belongs
+// to, and any extra imports the schema's column types require.
+type Meta struct {
+	Username     string
+	Timestamp    time.Time
+	PackageName  string
+	OtherImports []string
+}
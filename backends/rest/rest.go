@@ -0,0 +1,44 @@
+// Package rest is a bdog Backend that renders an introspected
+// schema.Schema into a net/http JSON API (tpl/bdog_rest.tpl) instead of
+// a Go SDK: one GET/POST/PUT/DELETE set of routes per table, plus
+// nested GET routes for each foreign key relationship.
+package rest
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/pbnjay/bdog/backends"
+	"github.com/pbnjay/bdog/schema"
+)
+
+// REST generates a net/http server exposing sc's tables as a
+// conventional REST/JSON API.
+type REST struct {
+	TemplateFile string
+	Meta         backends.Meta
+}
+
+// New returns a REST backend that renders tplFile with meta.
+func New(tplFile string, meta backends.Meta) *REST {
+	return &REST{TemplateFile: tplFile, Meta: meta}
+}
+
+func (rb *REST) Generate(sc *schema.Schema, w io.Writer) error {
+	tpl, err := template.ParseFiles(rb.TemplateFile)
+	if err != nil {
+		return err
+	}
+
+	return tpl.Execute(w, struct {
+		backends.Meta
+		Tables     map[string]schema.StructVars
+		Joins      map[string]schema.JoinVars
+		ManyToMany map[string]schema.ManyToMany
+	}{
+		Meta:       rb.Meta,
+		Tables:     sc.Tables,
+		Joins:      sc.Joins,
+		ManyToMany: sc.ManyToMany,
+	})
+}
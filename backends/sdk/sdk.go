@@ -0,0 +1,44 @@
+// Package sdk is bdog's default Backend: it renders an introspected
+// schema.Schema through a text/template into a standalone CRUD Go SDK,
+// the same output bdog always produced before backends were split out.
+package sdk
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/pbnjay/bdog/backends"
+	"github.com/pbnjay/bdog/schema"
+)
+
+// SDK generates a hand-rolled database/sql CRUD SDK (tpl/bdog.tpl), or
+// a github.com/jmoiron/sqlx one (tpl/bdog_sqlx.tpl) if TemplateFile is
+// set to it.
+type SDK struct {
+	TemplateFile string
+	Meta         backends.Meta
+}
+
+// New returns an SDK backend that renders tplFile with meta.
+func New(tplFile string, meta backends.Meta) *SDK {
+	return &SDK{TemplateFile: tplFile, Meta: meta}
+}
+
+func (s *SDK) Generate(sc *schema.Schema, w io.Writer) error {
+	tpl, err := template.ParseFiles(s.TemplateFile)
+	if err != nil {
+		return err
+	}
+
+	return tpl.Execute(w, struct {
+		backends.Meta
+		Tables     map[string]schema.StructVars
+		Joins      map[string]schema.JoinVars
+		ManyToMany map[string]schema.ManyToMany
+	}{
+		Meta:       s.Meta,
+		Tables:     sc.Tables,
+		Joins:      sc.Joins,
+		ManyToMany: sc.ManyToMany,
+	})
+}
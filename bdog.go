@@ -20,14 +20,22 @@ import (
 	"database/sql"
 	"flag"
 	"fmt"
+	_ "github.com/denisenkom/go-mssqldb"
+	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 	"go/format"
 	"os"
 	"os/user"
 	"regexp"
 	"strings"
-	"text/template"
 	"time"
+
+	"github.com/pbnjay/bdog/backends"
+	"github.com/pbnjay/bdog/backends/rest"
+	"github.com/pbnjay/bdog/backends/sdk"
+	"github.com/pbnjay/bdog/dialects"
+	"github.com/pbnjay/bdog/schema"
 )
 
 // map from plural to singular
@@ -78,7 +86,7 @@ func SingleCamel(undered_word string) string {
 			s = r2.ReplaceAllString(s, "$1")
 			s = r3.ReplaceAllString(s, "$1")
 		}
-		result += strings.Title(s)
+		result += capitalize(s)
 	}
 	return result
 }
@@ -89,7 +97,7 @@ func MultiCamel(undered_word string) string {
 	result := ""
 	parts := strings.Split(undered_word, "_")
 	for _, w := range parts {
-		result += strings.Title(w)
+		result += capitalize(w)
 	}
 	return result
 }
@@ -98,99 +106,71 @@ func MultiCamel(undered_word string) string {
 // Postgresql-specific functions
 
 type BDOGDatabase struct {
-	db  *sql.DB
-	buf bytes.Buffer
+	db      *sql.DB
+	dialect dialects.Dialect
+	buf     bytes.Buffer
+
+	tables     map[string]schema.StructVars
+	foreigns   map[string]schema.JoinVars
+	manyToMany map[string]schema.ManyToMany
 
-	tables   map[string]StructVars
-	foreigns map[string]JoinVars
+	// typeOverrides comes from Config.DataTypes, and takes priority over
+	// the dialect's own GoType().
+	typeOverrides map[string]DataTypeOverride
+	extraImports  []string
 
 	useTime bool
 	useNet  bool
 }
 
-var (
-	COLS_SQL = `
-    select table_schema, table_name, column_name, udt_name, is_nullable::bool, column_default
-      from information_schema.columns
-     where table_schema NOT IN ('pg_catalog','information_schema');`
-
-	FK_SQL = `
-	SELECT tc.constraint_name, tc.table_schema, tc.table_name, kcu.column_name,
-		   ccu.table_schema as f_table_schema, ccu.table_name AS f_table_name, ccu.column_name AS f_column_name
-	  FROM information_schema.table_constraints tc, information_schema.key_column_usage kcu,
-	       information_schema.constraint_column_usage ccu
-	 WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.constraint_name = kcu.constraint_name AND ccu.constraint_name = tc.constraint_name;`
-
-	PK_SQL = `
-	SELECT tc.table_schema, tc.table_name, kcu.column_name
-	  FROM information_schema.table_constraints tc, information_schema.key_column_usage kcu
-	 WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.constraint_name = kcu.constraint_name;`
-
-	DATATYPE_MAP = map[string]string{
-		"bool":        "bool",
-		"bytea":       "[]byte",
-		"int2":        "int16",
-		"int4":        "int32",
-		"int8":        "int64",
-		"float4":      "float32",
-		"float8":      "float64",
-		"numeric":     "float64", // this REALLY needs a good replacement
-		"money":       "float64", // this REALLY needs a good replacement
-		"char":        "string",
-		"varchar":     "string",
-		"text":        "string",
-		"xml":         "string",
-		"uuid":        "string",
-		"macaddr":     "net.HardwareAddr",
-		"inet":        "net.IP", // technically this could be IPNet too
-		"cidr":        "net.IPNet",
-		"date":        "time.Time",
-		"time":        "time.Time",
-		"timestamp":   "time.Time",
-		"timestamptz": "time.Time",
-		"timetz":      "time.Time",
-
-		/*
-			"abstime":     "",
-			"reltime":     "",
-			"interval":    "",
-			"tinterval":   "",
-			"bit":         "",
-			"varbit":      "",
-			"tsvector":    "",
-			"tsquery":     "",*/
+// addImport records pkg as a required import, if it isn't already.
+func (d *BDOGDatabase) addImport(pkg string) {
+	for _, p := range d.extraImports {
+		if p == pkg {
+			return
+		}
 	}
-)
+	d.extraImports = append(d.extraImports, pkg)
+}
 
-func (d *BDOGDatabase) Open(username, dbname string) (err error) {
-	connstr := fmt.Sprintf("user='%s' dbname='%s' sslmode=disable", username, dbname)
-	d.db, err = sql.Open("postgres", connstr)
+func (d *BDOGDatabase) Open(dialect dialects.Dialect, username, dbname string) (err error) {
+	d.dialect = dialect
+	d.db, err = sql.Open(dialect.DriverName(), dialect.ConnString(username, dbname))
 	if err != nil {
 		return err
 	}
 
 	d.useTime = false
 	d.useNet = false
-	d.tables = make(map[string]StructVars)
-	d.foreigns = make(map[string]JoinVars)
+	d.tables = make(map[string]schema.StructVars)
+	d.foreigns = make(map[string]schema.JoinVars)
 
-	rows, err := d.db.Query(COLS_SQL)
+	rows, err := d.db.Query(d.dialect.ColumnsQuery())
 	if err != nil {
 		return err
 	}
 
 	for rows.Next() {
-		var schema, name, column, datatype string
+		var tabschema, name, column, datatype string
 		var col_default *string // can be null
-		var is_nullable bool
+		var is_nullable, is_autoinc bool
 
-		err := rows.Scan(&schema, &name, &column, &datatype, &is_nullable, &col_default)
+		err := rows.Scan(&tabschema, &name, &column, &datatype, &is_nullable, &col_default, &is_autoinc)
 		if err != nil {
 			return err
 		}
 
 		// hopefully we have a type mapping...
-		gotype, hasgotype := DATATYPE_MAP[datatype]
+		var gotype string
+		var hasgotype bool
+		if override, ok := d.typeOverrides[datatype]; ok {
+			gotype, hasgotype = override.GoType, true
+			if override.Import != "" {
+				d.addImport(override.Import)
+			}
+		} else {
+			gotype, hasgotype = d.dialect.GoType(datatype)
+		}
 		if !hasgotype {
 			gotype = "sql.FIXME." + datatype
 		}
@@ -200,20 +180,20 @@ func (d *BDOGDatabase) Open(username, dbname string) (err error) {
 			nullstar = "*"
 		}
 
-		tref := schema + "." + name
+		tref := tabschema + "." + name
 		sv, ok := d.tables[tref]
 		if !ok {
-			sv = StructVars{
+			sv = schema.StructVars{
 				TableName:    name,
 				TableRef:     tref,
 				StructName:   SingleCamel(name),
 				PluralName:   MultiCamel(name),
 				V:            name[:1],
-				StructFields: make(map[string]StructField),
+				StructFields: make(map[string]schema.StructField),
 			}
 		}
 
-		sf := StructField{
+		sf := schema.StructField{
 			GoName:     MultiCamel(column),
 			GoType:     nullstar + gotype,
 			DBName:     column,
@@ -221,11 +201,10 @@ func (d *BDOGDatabase) Open(username, dbname string) (err error) {
 			DBNullable: is_nullable,
 		}
 
-		if col_default != nil && len(*col_default) > 10 && (*col_default)[:8] == "nextval(" {
-			sf.DBAutoInc = true
+		sf.DBAutoInc = is_autoinc
+		if is_autoinc {
 			sf.DBDefault = nil
 		} else {
-			sf.DBAutoInc = false
 			sf.DBDefault = col_default
 		}
 
@@ -250,7 +229,7 @@ func (d *BDOGDatabase) Open(username, dbname string) (err error) {
 
 	//////
 	// Primary Keys
-	rows, err = d.db.Query(PK_SQL)
+	rows, err = d.db.Query(d.dialect.PrimaryKeysQuery())
 	if err != nil {
 		return err
 	}
@@ -278,7 +257,7 @@ func (d *BDOGDatabase) Open(username, dbname string) (err error) {
 
 	//////
 	// Foreign Keys
-	rows, err = d.db.Query(FK_SQL)
+	rows, err = d.db.Query(d.dialect.ForeignKeysQuery())
 	if err != nil {
 		return err
 	}
@@ -299,7 +278,7 @@ func (d *BDOGDatabase) Open(username, dbname string) (err error) {
 		f.Other = d.tables[b_schema+"."+b_name]
 
 		// intermediate format for ordering...
-		f.foreignkeys = append(f.foreignkeys, a_column+"."+b_column)
+		f.ForeignKeys = append(f.ForeignKeys, a_column+"."+b_column)
 		d.foreigns[fkname] = f
 	}
 
@@ -309,9 +288,10 @@ func (d *BDOGDatabase) Open(username, dbname string) (err error) {
 
 	// order foreign keys to match StructFields
 	for fkname, jv := range d.foreigns {
-		if len(jv.foreignkeys) == 1 {
-			parts := strings.Split(jv.foreignkeys[0], ".")
-			jv.foreignkeys = []string{parts[0]}
+		if len(jv.ForeignKeys) == 1 {
+			parts := strings.Split(jv.ForeignKeys[0], ".")
+			jv.ForeignKeys = []string{parts[0]}
+			d.foreigns[fkname] = jv
 			continue
 		}
 
@@ -320,15 +300,15 @@ func (d *BDOGDatabase) Open(username, dbname string) (err error) {
 			if !sf.DBPrimaryKey {
 				continue
 			}
-			for _, rel := range jv.foreignkeys {
+			for _, rel := range jv.ForeignKeys {
 				parts := strings.Split(rel, ".")
 				if parts[1] == dbname {
 					newfk = append(newfk, parts[0])
 				}
 			}
 		}
-		f := d.foreigns[fkname]
-		f.foreignkeys = newfk
+		jv.ForeignKeys = newfk
+		d.foreigns[fkname] = jv
 	}
 
 	// a hack because map enumeration order isn't respected in templates
@@ -338,9 +318,74 @@ func (d *BDOGDatabase) Open(username, dbname string) (err error) {
 		}
 		d.tables[tabpath] = sv
 	}
+
+	// refresh the Base/Other copies cached in d.foreigns now that
+	// StructFieldsOrder is populated on d.tables
+	for fkname, jv := range d.foreigns {
+		jv.Base = d.tables[jv.Base.TableRef]
+		jv.Other = d.tables[jv.Other.TableRef]
+		d.foreigns[fkname] = jv
+	}
+
+	d.findManyToMany()
 	return nil
 }
 
+// findManyToMany scans the resolved foreign keys for join tables: ones
+// that own exactly two foreign keys into two other (distinct) tables.
+// Any columns not covered by those foreign keys are kept as ExtraCols,
+// so a "pure join" table is simply one with no ExtraCols. Each join
+// table gets a ManyToMany entry so templates can emit two-hop relation
+// methods on both endpoint structs; the join table itself stays in
+// d.tables and its foreign keys stay in d.foreigns.
+func (d *BDOGDatabase) findManyToMany() {
+	d.manyToMany = make(map[string]schema.ManyToMany)
+
+	outgoing := make(map[string][]string) // TableRef -> fk names owned by that table
+	for fkname, jv := range d.foreigns {
+		outgoing[jv.Base.TableRef] = append(outgoing[jv.Base.TableRef], fkname)
+	}
+
+	for tabref, fknames := range outgoing {
+		if len(fknames) != 2 {
+			continue
+		}
+
+		left := d.foreigns[fknames[0]]
+		right := d.foreigns[fknames[1]]
+		if left.Other.TableRef == right.Other.TableRef {
+			// both FKs point at the same table; not a join table
+			continue
+		}
+		if left.Other.TableRef == tabref || right.Other.TableRef == tabref {
+			// a self-referencing FK; not a join table
+			continue
+		}
+
+		fkcols := make(map[string]bool)
+		for _, c := range left.ForeignKeys {
+			fkcols[c] = true
+		}
+		for _, c := range right.ForeignKeys {
+			fkcols[c] = true
+		}
+
+		var extra []schema.StructField
+		for _, sf := range d.tables[tabref].StructFieldsOrder {
+			if !fkcols[sf.DBName] {
+				extra = append(extra, sf)
+			}
+		}
+
+		d.manyToMany[tabref] = schema.ManyToMany{
+			JoinTable: d.tables[tabref],
+			Left:      left,
+			Right:     right,
+			ExtraCols: extra,
+		}
+	}
+}
+
 func GetTableNames(d *BDOGDatabase) (map[string]string, error) {
 	nargs := flag.NArg()
 	ntabs := len(os.Args) - nargs
@@ -379,57 +424,100 @@ func GetInitFile(d *BDOGDatabase) {
 	fmt.Fprintf(&d.buf, `
 func init() {
 	// FIXME: remove hard-coded connection params here
-	connstr := "user='%s' dbname='%s' sslmode=disable"
-	Db, err = sql.Open("postgres", connstr)
+	connstr := %q
+	Db, err = sql.Open(%q, connstr)
 	if err != nil {
 		return err
 	}
 }
-		`, db_user, db_name)
+		`, d.dialect.ConnString(db_user, db_name), d.dialect.DriverName())
 }
 
 //////////////
 
 var (
-	depluralize DepluralizeMap
-	db_user     string
-	db_name     string
-	out_package string
+	depluralize  DepluralizeMap
+	db_user      string
+	db_name      string
+	db_driver    string
+	out_package  string
+	out_orm      string
+	out_config   string
+	out_fixtures string
+	out_backend  string
 )
 
 func init() {
 	flag.Var(&depluralize, "deplural", "optional map from plural to singular words. (words:word,others:other)")
 	flag.StringVar(&db_user, "user", "(username)", "database username")
 	flag.StringVar(&db_name, "name", "(dbname)", "database name")
+	flag.StringVar(&db_driver, "driver", "postgres", "database driver (postgres, mysql, sqlite, mssql)")
 	flag.StringVar(&out_package, "pkg", "models", "package name")
+	flag.StringVar(&out_orm, "orm", "", "output mode: \"\" for hand-rolled database/sql, \"sqlx\" for github.com/jmoiron/sqlx")
+	flag.StringVar(&out_config, "config", "", "optional YAML config file for naming, type mapping, and per-column overrides")
+	flag.StringVar(&out_fixtures, "fixtures", "", "write testfixtures-compatible YAML seed skeletons and a fixtures_test.go into this directory, instead of generating SDK code")
+	flag.StringVar(&out_backend, "backend", "sdk", "output backend: \"sdk\" for a standalone Go CRUD SDK, \"rest\" for a net/http JSON API")
 
-	// TODO: support custom column naming/capitalization through config file (YAML?)
-	// TODO: support custom column type mapping through config file (YAML?)
 	// TODO: output basic doc.go skeleton (option)
-
-	// TODO: transparently support Many-to-Many relationships
-	//       - either w/ no non-fk columns only,
-	//			 - or w/ non-fk columns mapped to merged relation structs (embedded?)
 }
 
 func main() {
 	flag.Parse()
 
-	tpl, err := template.ParseFiles("tpl/bdog.tpl")
+	dialect, err := dialects.Get(db_driver)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
+	schema.CurrentDialect = dialect
+
+	var cfg *Config
+	if out_config != "" {
+		cfg, err = LoadConfig(out_config)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if len(cfg.Deplural) > 0 {
+			depluralize.words = cfg.Deplural
+		}
+		setAcronyms(cfg.Acronyms)
+	}
 
 	db := &BDOGDatabase{}
-	err = db.Open(db_user, db_name)
+	if cfg != nil {
+		typeOverrides := make(map[string]DataTypeOverride, len(cfg.DataTypes))
+		for k, v := range cfg.DataTypes {
+			typeOverrides[k] = v
+		}
+		db.typeOverrides = typeOverrides
+	}
+
+	err = db.Open(dialect, db_user, db_name)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
+	if cfg != nil {
+		if err := cfg.Validate(db); err != nil {
+			fmt.Println(err)
+			return
+		}
+		cfg.Apply(db)
+	}
+
+	if out_fixtures != "" {
+		if err := GenerateFixtures(out_fixtures, out_package, db.tables); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("wrote fixture skeletons and fixtures_test.go to %s\n", out_fixtures)
+		return
+	}
+
 	usr, _ := user.Current()
-	alsoneed := []string{}
+	alsoneed := append([]string{}, db.extraImports...)
 	if db.useTime {
 		alsoneed = append(alsoneed, "time")
 	}
@@ -437,23 +525,30 @@ func main() {
 		alsoneed = append(alsoneed, "net")
 	}
 
-	err = tpl.Execute(&db.buf, struct {
-		Username     string
-		Timestamp    time.Time
-		PackageName  string
-		OtherImports []string
-		Tables       map[string]StructVars
-		Joins        map[string]JoinVars
-	}{
+	meta := backends.Meta{
 		Username:     usr.Name,
 		Timestamp:    time.Now(),
 		PackageName:  out_package,
 		OtherImports: alsoneed,
-		Tables:       db.tables,
-		Joins:        db.foreigns,
-	})
+	}
 
-	if err != nil {
+	var backend backends.Backend
+	switch out_backend {
+	case "rest":
+		backend = rest.New("tpl/bdog_rest.tpl", meta)
+	case "sdk", "":
+		tplFile := "tpl/bdog.tpl"
+		if out_orm == "sqlx" {
+			tplFile = "tpl/bdog_sqlx.tpl"
+		}
+		backend = sdk.New(tplFile, meta)
+	default:
+		fmt.Printf("bdog: unknown -backend %q (want \"sdk\" or \"rest\")\n", out_backend)
+		return
+	}
+
+	sc := &schema.Schema{Tables: db.tables, Joins: db.foreigns, ManyToMany: db.manyToMany}
+	if err := backend.Generate(sc, &db.buf); err != nil {
 		fmt.Println(err)
 	}
 
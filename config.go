@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pbnjay/bdog/schema"
+)
+
+// Config holds user-supplied overrides for naming, type mapping, and
+// per-column behavior, loaded from the file given by -config. It lets
+// callers fix up the defaults bdog infers from the schema alone, without
+// patching the generated code by hand every run.
+type Config struct {
+	// DataTypes adds to or overrides the -driver dialect's own type
+	// mapping (dialects.Dialect.GoType), keyed on the native column type
+	// name (eg. "numeric").
+	DataTypes map[string]DataTypeOverride `yaml:"datatypes"`
+
+	// Deplural supersedes the -deplural flag when set.
+	Deplural map[string]string `yaml:"deplural"`
+
+	// Acronyms are fully capitalized wherever they appear as a whole
+	// underscore-separated word in SingleCamel/MultiCamel output, eg.
+	// "id" -> "ID", "url" -> "URL", "http" -> "HTTP".
+	Acronyms []string `yaml:"acronyms"`
+
+	// Tables holds per-table overrides, keyed on "schema.table" or the
+	// bare table name.
+	Tables map[string]TableOverride `yaml:"tables"`
+}
+
+// DataTypeOverride customizes the Go type used for a native column type,
+// optionally requiring an extra import (eg. shopspring/decimal.Decimal).
+type DataTypeOverride struct {
+	GoType string `yaml:"type"`
+	Import string `yaml:"import"`
+}
+
+// TableOverride customizes naming and per-column behavior for one table.
+type TableOverride struct {
+	GoName  string                    `yaml:"name"`
+	Skip    bool                      `yaml:"skip"`
+	Columns map[string]ColumnOverride `yaml:"columns"`
+}
+
+// ColumnOverride customizes naming, typing, and key/skip flags for one
+// column.
+type ColumnOverride struct {
+	GoName     string `yaml:"name"`
+	GoType     string `yaml:"type"`
+	ScanType   string `yaml:"scan_type"`
+	PrimaryKey *bool  `yaml:"primary_key"`
+	Skip       bool   `yaml:"skip"`
+}
+
+// LoadConfig reads and parses a bdog YAML config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("bdog: reading -config %s: %w", path, err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("bdog: parsing -config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks that every table/column referenced by cfg actually
+// exists in the introspected schema, so typos fail fast instead of
+// silently generating nothing.
+func (cfg *Config) Validate(d *BDOGDatabase) error {
+	for tabref, tov := range cfg.Tables {
+		sv, ok := d.findTable(tabref)
+		if !ok {
+			return fmt.Errorf("bdog: -config references unknown table %q", tabref)
+		}
+		for col := range tov.Columns {
+			if _, ok := sv.StructFields[col]; !ok {
+				return fmt.Errorf("bdog: -config references unknown column %q on table %q", col, tabref)
+			}
+		}
+	}
+	return nil
+}
+
+// findTable looks up a table by its "schema.table" ref or bare name.
+func (d *BDOGDatabase) findTable(ref string) (schema.StructVars, bool) {
+	if sv, ok := d.tables[ref]; ok {
+		return sv, true
+	}
+	for _, sv := range d.tables {
+		if sv.TableName == ref {
+			return sv, true
+		}
+	}
+	return schema.StructVars{}, false
+}
+
+// Apply rewrites d.tables in place according to cfg: renaming tables and
+// columns, overriding Go types and scan types, marking primary keys, and
+// dropping skipped tables/columns.
+func (cfg *Config) Apply(d *BDOGDatabase) {
+	for tabref, tov := range cfg.Tables {
+		tableKey := tabref
+		sv, ok := d.tables[tabref]
+		if !ok {
+			for k, other := range d.tables {
+				if other.TableName == tabref {
+					tableKey, sv = k, other
+					break
+				}
+			}
+		}
+
+		if tov.Skip {
+			delete(d.tables, tableKey)
+			dropTableReferences(d, tableKey)
+			continue
+		}
+		if tov.GoName != "" {
+			sv.StructName = tov.GoName
+		}
+
+		for col, cov := range tov.Columns {
+			sf, ok := sv.StructFields[col]
+			if !ok {
+				continue
+			}
+			if cov.Skip {
+				delete(sv.StructFields, col)
+				continue
+			}
+			if cov.GoName != "" {
+				sf.GoName = cov.GoName
+			}
+			if cov.GoType != "" {
+				sf.GoType = cov.GoType
+			}
+			if cov.ScanType != "" {
+				sf.ScanType = cov.ScanType
+			}
+			if cov.PrimaryKey != nil {
+				sf.DBPrimaryKey = *cov.PrimaryKey
+			}
+			sv.StructFields[col] = sf
+		}
+
+		sv.StructFieldsOrder = sv.StructFieldsOrder[:0]
+		for _, sf := range sv.StructFields {
+			sv.StructFieldsOrder = append(sv.StructFieldsOrder, sf)
+		}
+		d.tables[tableKey] = sv
+	}
+}
+
+// dropTableReferences removes any Join or ManyToMany entry touching
+// tableKey, so a skipped table's struct can't still be referenced by
+// relation methods or routes generated for the tables that remain.
+func dropTableReferences(d *BDOGDatabase, tableKey string) {
+	for fkname, jv := range d.foreigns {
+		if jv.Base.TableRef == tableKey || jv.Other.TableRef == tableKey {
+			delete(d.foreigns, fkname)
+		}
+	}
+	for tabref, m := range d.manyToMany {
+		if tabref == tableKey || m.Left.Other.TableRef == tableKey || m.Right.Other.TableRef == tableKey {
+			delete(d.manyToMany, tabref)
+		}
+	}
+}
+
+// acronyms holds the lower-cased words that SingleCamel/MultiCamel
+// should render fully capitalized (eg. "id" -> "ID"), loaded from
+// Config.Acronyms.
+var acronyms = map[string]bool{}
+
+func setAcronyms(words []string) {
+	for _, w := range words {
+		acronyms[strings.ToLower(w)] = true
+	}
+}
+
+// capitalize title-cases w, unless it's a configured acronym, in which
+// case it's rendered fully upper-case.
+func capitalize(w string) string {
+	if acronyms[strings.ToLower(w)] {
+		return strings.ToUpper(w)
+	}
+	return strings.Title(w)
+}
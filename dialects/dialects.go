@@ -0,0 +1,74 @@
+// Package dialects abstracts the schema-introspection queries and SQL
+// generation quirks that differ between the database engines bdog can
+// reverse-engineer. Each supported engine implements the Dialect
+// interface and registers itself with Register so it can be selected
+// with the `-driver` flag.
+package dialects
+
+import "fmt"
+
+// Dialect carries everything bdog needs to introspect a schema and emit
+// working SQL for one particular database engine.
+type Dialect interface {
+	// Name is the -driver flag value that selects this dialect.
+	Name() string
+
+	// DriverName is the database/sql driver name passed to sql.Open.
+	DriverName() string
+
+	// ConnString builds a database/sql connection string for the given
+	// username and database name.
+	ConnString(username, dbname string) string
+
+	// ColumnsQuery returns a query yielding one row per column:
+	// (table_schema, table_name, column_name, data_type, is_nullable,
+	// column_default, is_autoinc)
+	ColumnsQuery() string
+
+	// PrimaryKeysQuery returns a query yielding one row per primary key
+	// column: (table_schema, table_name, column_name)
+	PrimaryKeysQuery() string
+
+	// ForeignKeysQuery returns a query yielding one row per foreign key
+	// column: (constraint_name, table_schema, table_name, column_name,
+	// f_table_schema, f_table_name, f_column_name)
+	ForeignKeysQuery() string
+
+	// GoType resolves nativeType, as reported by this dialect's
+	// ColumnsQuery, to a Go type. ok is false for unmapped types.
+	GoType(nativeType string) (goType string, ok bool)
+
+	// Placeholder returns the bound-parameter placeholder for the i'th
+	// (1-based) argument in a query.
+	Placeholder(i int) string
+
+	// Quote returns ident quoted as an identifier for this dialect.
+	Quote(ident string) string
+}
+
+var registry = make(map[string]Dialect)
+
+// Register adds a Dialect to the registry under its own Name().
+func Register(d Dialect) {
+	registry[d.Name()] = d
+}
+
+// Get looks up a registered Dialect by -driver flag name.
+func Get(name string) (Dialect, error) {
+	d, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("bdog: unknown -driver %q (supported: %s)", name, supportedNames())
+	}
+	return d, nil
+}
+
+func supportedNames() string {
+	names := ""
+	for n := range registry {
+		if names != "" {
+			names += ", "
+		}
+		names += n
+	}
+	return names
+}
@@ -0,0 +1,89 @@
+package dialects
+
+import "fmt"
+
+func init() {
+	Register(&MSSQL{})
+}
+
+// MSSQL introspects schema via the sys.* catalog views.
+type MSSQL struct{}
+
+func (s *MSSQL) Name() string       { return "mssql" }
+func (s *MSSQL) DriverName() string { return "sqlserver" }
+
+func (s *MSSQL) ConnString(username, dbname string) string {
+	return fmt.Sprintf("sqlserver://%s@localhost?database=%s", username, dbname)
+}
+
+func (s *MSSQL) ColumnsQuery() string {
+	return `
+	SELECT sch.name, tab.name, col.name, ty.name, col.is_nullable, dc.definition, col.is_identity
+	  FROM sys.columns col
+	  JOIN sys.tables tab ON tab.object_id = col.object_id
+	  JOIN sys.schemas sch ON sch.schema_id = tab.schema_id
+	  JOIN sys.types ty ON ty.user_type_id = col.user_type_id
+	  LEFT JOIN sys.default_constraints dc ON dc.object_id = col.default_object_id;`
+}
+
+func (s *MSSQL) PrimaryKeysQuery() string {
+	return `
+	SELECT sch.name, tab.name, col.name
+	  FROM sys.index_columns ic
+	  JOIN sys.indexes i ON i.object_id = ic.object_id AND i.index_id = ic.index_id
+	  JOIN sys.tables tab ON tab.object_id = ic.object_id
+	  JOIN sys.schemas sch ON sch.schema_id = tab.schema_id
+	  JOIN sys.columns col ON col.object_id = ic.object_id AND col.column_id = ic.column_id
+	 WHERE i.is_primary_key = 1;`
+}
+
+func (s *MSSQL) ForeignKeysQuery() string {
+	return `
+	SELECT fk.name, sch.name, tab.name, col.name,
+	       fsch.name, ftab.name, fcol.name
+	  FROM sys.foreign_keys fk
+	  JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+	  JOIN sys.tables tab ON tab.object_id = fkc.parent_object_id
+	  JOIN sys.schemas sch ON sch.schema_id = tab.schema_id
+	  JOIN sys.columns col ON col.object_id = fkc.parent_object_id AND col.column_id = fkc.parent_column_id
+	  JOIN sys.tables ftab ON ftab.object_id = fkc.referenced_object_id
+	  JOIN sys.schemas fsch ON fsch.schema_id = ftab.schema_id
+	  JOIN sys.columns fcol ON fcol.object_id = fkc.referenced_object_id AND fcol.column_id = fkc.referenced_column_id;`
+}
+
+// GoType resolves nativeType against mssqlTypeMap.
+func (s *MSSQL) GoType(nativeType string) (string, bool) {
+	t, ok := mssqlTypeMap[nativeType]
+	return t, ok
+}
+
+var mssqlTypeMap = map[string]string{
+	"bit":              "bool",
+	"tinyint":          "int8",
+	"smallint":         "int16",
+	"int":              "int32",
+	"bigint":           "int64",
+	"real":             "float32",
+	"float":            "float64",
+	"decimal":          "float64", // this REALLY needs a good replacement
+	"numeric":          "float64", // this REALLY needs a good replacement
+	"money":            "float64",
+	"char":             "string",
+	"varchar":          "string",
+	"nchar":            "string",
+	"nvarchar":         "string",
+	"text":             "string",
+	"ntext":            "string",
+	"uniqueidentifier": "string",
+	"binary":           "[]byte",
+	"varbinary":        "[]byte",
+	"date":             "time.Time",
+	"time":             "time.Time",
+	"datetime":         "time.Time",
+	"datetime2":        "time.Time",
+	"smalldatetime":    "time.Time",
+}
+
+func (s *MSSQL) Placeholder(i int) string { return fmt.Sprintf("@p%d", i) }
+
+func (s *MSSQL) Quote(ident string) string { return "[" + ident + "]" }
@@ -0,0 +1,75 @@
+package dialects
+
+import "fmt"
+
+func init() {
+	Register(&MySQL{})
+}
+
+// MySQL introspects schema via information_schema, same as Postgres, but
+// with backtick quoting and `?` placeholders.
+type MySQL struct{}
+
+func (m *MySQL) Name() string       { return "mysql" }
+func (m *MySQL) DriverName() string { return "mysql" }
+
+func (m *MySQL) ConnString(username, dbname string) string {
+	return fmt.Sprintf("%s@/%s", username, dbname)
+}
+
+func (m *MySQL) ColumnsQuery() string {
+	return `
+    select table_schema, table_name, column_name, data_type, is_nullable = 'YES', column_default,
+           extra = 'auto_increment'
+      from information_schema.columns
+     where table_schema = database();`
+}
+
+func (m *MySQL) PrimaryKeysQuery() string {
+	return `
+	SELECT table_schema, table_name, column_name
+	  FROM information_schema.key_column_usage
+	 WHERE table_schema = database() AND constraint_name = 'PRIMARY';`
+}
+
+func (m *MySQL) ForeignKeysQuery() string {
+	return `
+	SELECT constraint_name, table_schema, table_name, column_name,
+	       referenced_table_schema, referenced_table_name, referenced_column_name
+	  FROM information_schema.key_column_usage
+	 WHERE table_schema = database() AND referenced_table_name IS NOT NULL;`
+}
+
+// GoType resolves nativeType against mysqlTypeMap.
+func (m *MySQL) GoType(nativeType string) (string, bool) {
+	t, ok := mysqlTypeMap[nativeType]
+	return t, ok
+}
+
+var mysqlTypeMap = map[string]string{
+	"tinyint":    "int8",
+	"smallint":   "int16",
+	"mediumint":  "int32",
+	"int":        "int32",
+	"bigint":     "int64",
+	"float":      "float32",
+	"double":     "float64",
+	"decimal":    "float64", // this REALLY needs a good replacement
+	"char":       "string",
+	"varchar":    "string",
+	"text":       "string",
+	"mediumtext": "string",
+	"longtext":   "string",
+	"enum":       "string",
+	"binary":     "[]byte",
+	"varbinary":  "[]byte",
+	"blob":       "[]byte",
+	"date":       "time.Time",
+	"time":       "time.Time",
+	"datetime":   "time.Time",
+	"timestamp":  "time.Time",
+}
+
+func (m *MySQL) Placeholder(i int) string { return "?" }
+
+func (m *MySQL) Quote(ident string) string { return "`" + ident + "`" }
@@ -0,0 +1,86 @@
+package dialects
+
+import "fmt"
+
+func init() {
+	Register(&Postgres{})
+}
+
+// Postgres is the original bdog dialect, backed by information_schema.
+type Postgres struct{}
+
+func (p *Postgres) Name() string       { return "postgres" }
+func (p *Postgres) DriverName() string { return "postgres" }
+
+func (p *Postgres) ConnString(username, dbname string) string {
+	return fmt.Sprintf("user='%s' dbname='%s' sslmode=disable", username, dbname)
+}
+
+func (p *Postgres) ColumnsQuery() string {
+	return `
+    select table_schema, table_name, column_name, udt_name, is_nullable::bool, column_default,
+           coalesce(column_default LIKE 'nextval(%', false)
+      from information_schema.columns
+     where table_schema NOT IN ('pg_catalog','information_schema');`
+}
+
+func (p *Postgres) PrimaryKeysQuery() string {
+	return `
+	SELECT tc.table_schema, tc.table_name, kcu.column_name
+	  FROM information_schema.table_constraints tc, information_schema.key_column_usage kcu
+	 WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.constraint_name = kcu.constraint_name;`
+}
+
+func (p *Postgres) ForeignKeysQuery() string {
+	return `
+	SELECT tc.constraint_name, tc.table_schema, tc.table_name, kcu.column_name,
+		   ccu.table_schema as f_table_schema, ccu.table_name AS f_table_name, ccu.column_name AS f_column_name
+	  FROM information_schema.table_constraints tc, information_schema.key_column_usage kcu,
+	       information_schema.constraint_column_usage ccu
+	 WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.constraint_name = kcu.constraint_name AND ccu.constraint_name = tc.constraint_name;`
+}
+
+// GoType resolves nativeType against postgresTypeMap.
+func (p *Postgres) GoType(nativeType string) (string, bool) {
+	t, ok := postgresTypeMap[nativeType]
+	return t, ok
+}
+
+var postgresTypeMap = map[string]string{
+	"bool":        "bool",
+	"bytea":       "[]byte",
+	"int2":        "int16",
+	"int4":        "int32",
+	"int8":        "int64",
+	"float4":      "float32",
+	"float8":      "float64",
+	"numeric":     "float64", // this REALLY needs a good replacement
+	"money":       "float64", // this REALLY needs a good replacement
+	"char":        "string",
+	"varchar":     "string",
+	"text":        "string",
+	"xml":         "string",
+	"uuid":        "string",
+	"macaddr":     "net.HardwareAddr",
+	"inet":        "net.IP", // technically this could be IPNet too
+	"cidr":        "net.IPNet",
+	"date":        "time.Time",
+	"time":        "time.Time",
+	"timestamp":   "time.Time",
+	"timestamptz": "time.Time",
+	"timetz":      "time.Time",
+
+	/*
+		"abstime":     "",
+		"reltime":     "",
+		"interval":    "",
+		"tinterval":   "",
+		"bit":         "",
+		"varbit":      "",
+		"tsvector":    "",
+		"tsquery":     "",*/
+}
+
+func (p *Postgres) Placeholder(i int) string { return fmt.Sprintf("$%d", i) }
+
+func (p *Postgres) Quote(ident string) string { return `"` + ident + `"` }
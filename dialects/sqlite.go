@@ -0,0 +1,85 @@
+package dialects
+
+import "strings"
+
+func init() {
+	Register(&SQLite{})
+}
+
+// SQLite introspects schema via the PRAGMA statements, since sqlite has
+// no information_schema. There is only ever one (unnamed) schema, so
+// table_schema is always reported as "main".
+type SQLite struct{}
+
+func (s *SQLite) Name() string       { return "sqlite" }
+func (s *SQLite) DriverName() string { return "sqlite3" }
+
+func (s *SQLite) ConnString(username, dbname string) string {
+	// sqlite has no users; dbname is a filesystem path to the db file.
+	return dbname
+}
+
+func (s *SQLite) ColumnsQuery() string {
+	// PRAGMA table_info() only ever covers one table at a time, so its
+	// table-valued form is joined against every table in sqlite_master:
+	//   pragma_table_info('<table>')  -- cid, name, type, notnull, dflt_value, pk
+	//
+	// A single INTEGER PRIMARY KEY column is a rowid alias and
+	// autoincrements, so that's the closest signal sqlite offers for
+	// is_autoinc.
+	return `
+	SELECT 'main', m.name, p.name, p.type, p.[notnull] = 0, p.dflt_value,
+	       (p.pk > 0 AND upper(p."type") = 'INTEGER'
+	        AND (SELECT count(*) FROM pragma_table_info(m.name) k WHERE k.pk > 0) = 1)
+	  FROM sqlite_master m, pragma_table_info(m.name) p
+	 WHERE m.type = 'table';`
+}
+
+func (s *SQLite) PrimaryKeysQuery() string {
+	return `
+	SELECT 'main', m.name, p.name
+	  FROM sqlite_master m, pragma_table_info(m.name) p
+	 WHERE m.type = 'table' AND p.pk > 0;`
+}
+
+func (s *SQLite) ForeignKeysQuery() string {
+	// PRAGMA foreign_key_list('<table>') columns: id, seq, table, from, to, ...
+	// f."id" only numbers a table's own foreign keys starting at 0, so it
+	// must be folded in with the owning table name to make a key that's
+	// unique across the whole schema.
+	return `
+	SELECT 'fk' || m.name || '_' || f."id", 'main', m.name, f."from", 'main', f."table", f."to"
+	  FROM sqlite_master m, pragma_foreign_key_list(m.name) f
+	 WHERE m.type = 'table';`
+}
+
+// GoType resolves nativeType to a Go type using SQLite's own type
+// affinity rules (https://www.sqlite.org/datatype3.html#determination_of_column_affinity),
+// since PRAGMA table_info reports the type exactly as declared in the
+// DDL: case varies, and there's no fixed type vocabulary to exact-match
+// against like the other dialects have.
+func (s *SQLite) GoType(nativeType string) (string, bool) {
+	t := strings.ToUpper(nativeType)
+	switch {
+	case t == "":
+		return "[]byte", true // column with no declared type has BLOB affinity
+	case t == "BOOLEAN":
+		return "bool", true
+	case t == "DATE" || t == "DATETIME":
+		return "time.Time", true
+	case strings.Contains(t, "INT"):
+		return "int64", true
+	case strings.Contains(t, "CHAR") || strings.Contains(t, "CLOB") || strings.Contains(t, "TEXT"):
+		return "string", true
+	case strings.Contains(t, "BLOB"):
+		return "[]byte", true
+	case strings.Contains(t, "REAL") || strings.Contains(t, "FLOA") || strings.Contains(t, "DOUB"):
+		return "float64", true
+	default:
+		return "float64", true // NUMERIC affinity catch-all
+	}
+}
+
+func (s *SQLite) Placeholder(i int) string { return "?" }
+
+func (s *SQLite) Quote(ident string) string { return `"` + ident + `"` }
@@ -0,0 +1,468 @@
+// Package schema holds the introspected-schema data model bdog builds
+// from a database, and the template helper methods that turn it into
+// SQL fragments. It's shared by package main (which builds it) and the
+// backends package (which renders it into generated code).
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pbnjay/bdog/dialects"
+)
+
+// CurrentDialect is set once in main() from the -driver flag, and is
+// read by the template helpers below so generated SQL uses the right
+// placeholder syntax for the target database.
+var CurrentDialect dialects.Dialect
+
+// goStringEscaper escapes the characters that would otherwise break out
+// of the double-quoted Go string literals the templates build their SQL
+// queries from.
+var goStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quoteIdent quotes ident as an identifier for CurrentDialect, then
+// escapes the result for safe embedding in a Go string literal. Dialects
+// like postgres and sqlite quote with ", which would otherwise terminate
+// the literal the templates splice it into.
+func quoteIdent(ident string) string {
+	return goStringEscaper.Replace(CurrentDialect.Quote(ident))
+}
+
+// Schema is the full result of introspecting a database: every table,
+// every foreign key between them, and every detected many-to-many join.
+// It's the single value passed to a Backend's Generate method.
+type Schema struct {
+	Tables     map[string]StructVars
+	Joins      map[string]JoinVars
+	ManyToMany map[string]ManyToMany
+}
+
+// StructField represents one database column and the Go struct field it
+// maps to.
+type StructField struct {
+	GoName       string
+	GoType       string
+	DBName       string
+	DBType       string
+	DBDefault    *string
+	DBPrimaryKey bool
+	DBAutoInc    bool
+	DBNullable   bool
+
+	// if GoType is a supported database/sql/driver.Value type,
+	// leave these blank
+	ScanName string // name of a local variable to use (ie str<GoName>)
+	ScanType string // must be a supported driver.Value type (ie string)
+}
+
+// StructVars represents a database table and supporting information. This
+// is used to generate a Go struct{} and supporting CRUD methods.
+type StructVars struct {
+	V                 string                 // mini-var name (ie "u" for User)
+	TableName         string                 // bare_table
+	TableRef          string                 // schema.bare_table
+	StructName        string                 // singular CamelCase of TableName
+	StructFields      map[string]StructField // keyed on db column name
+	StructFieldsOrder []StructField          // because Go templates love to sort the map...
+	PluralName        string                 // plural CamelCase of TableName
+}
+
+// JoinVars represent a foreign key relationship between two tables. This
+// is used to generate methods to enumerate relations.
+type JoinVars struct {
+	Base        StructVars
+	Other       StructVars
+	ForeignKeys []string // as Base.DBNames
+}
+
+// ManyToMany represents a join table: one owning two foreign keys into
+// Left and Right. A "pure join" table's columns are entirely covered by
+// those foreign keys; any other columns are kept in ExtraCols. It is used
+// to generate two-hop relation methods on both endpoint structs (eg.
+// `(u *User) Groups(...)` and `(g *Group) Users(...)`) plus Add/Remove
+// helpers that insert/delete rows in JoinTable.
+type ManyToMany struct {
+	JoinTable StructVars
+	Left      JoinVars      // Base=JoinTable, Other=left endpoint
+	Right     JoinVars      // Base=JoinTable, Other=right endpoint
+	ExtraCols []StructField // non-FK columns, if any (embedded relation data)
+}
+
+// HasExtraCols reports whether JoinTable carries columns beyond the two
+// foreign keys, meaning callers need an embedded relation struct instead
+// of a bare two-hop SELECT.
+func (m ManyToMany) HasExtraCols() bool {
+	return len(m.ExtraCols) > 0
+}
+
+// joinAlias returns an alias for JoinTable to use in a two-table query
+// that also joins against other, falling back to a disambiguated alias
+// in the (common, since both default to their name's first letter) case
+// where JoinTable.V would otherwise collide with other.V.
+func (m ManyToMany) joinAlias(other StructVars) string {
+	if m.JoinTable.V == other.V {
+		return m.JoinTable.V + "j"
+	}
+	return m.JoinTable.V
+}
+
+// LeftJoinAlias returns JoinTable's alias for the query that also selects
+// Left.Other (see LeftJoinOn), disambiguated from Left.Other.V if needed.
+func (m ManyToMany) LeftJoinAlias() string {
+	return m.joinAlias(m.Left.Other)
+}
+
+// RightJoinAlias returns JoinTable's alias for the query that also
+// selects Right.Other (see RightJoinOn), disambiguated from
+// Right.Other.V if needed.
+func (m ManyToMany) RightJoinAlias() string {
+	return m.joinAlias(m.Right.Other)
+}
+
+// ExtraColsSelect returns ExtraCols qualified with alias, for use in the
+// select list of a two-hop relation query that embeds them alongside
+// the endpoint struct.
+func (m ManyToMany) ExtraColsSelect(alias string) string {
+	r := []string{}
+	for _, f := range m.ExtraCols {
+		r = append(r, fmt.Sprintf("%s.%s", alias, quoteIdent(f.DBName)))
+	}
+	return strings.Join(r, ", ")
+}
+
+// JoinTableCols returns JoinTable's own foreign key column names, Left's
+// first then Right's, for use in the Add/Remove helpers below.
+func (m ManyToMany) JoinTableCols() string {
+	cols := append(append([]string{}, m.Left.ForeignKeys...), m.Right.ForeignKeys...)
+	for i, c := range cols {
+		cols[i] = quoteIdent(c)
+	}
+	return strings.Join(cols, ", ")
+}
+
+// JoinTablePlaceholders returns the bound-parameter placeholders matching
+// JoinTableCols, for use in an INSERT statement.
+func (m ManyToMany) JoinTablePlaceholders() string {
+	n := len(m.Left.ForeignKeys) + len(m.Right.ForeignKeys)
+	r := make([]string, n)
+	for i := 0; i < n; i++ {
+		r[i] = CurrentDialect.Placeholder(i + 1)
+	}
+	return strings.Join(r, ", ")
+}
+
+// LeftWhere returns JoinTable's Left foreign key columns, aliased for the
+// query that also selects Right.Other (see RightJoinAlias), matched
+// against placeholder parameters bound to Left.Other's primary key
+// values.
+func (m ManyToMany) LeftWhere() string {
+	return m.sideWhere(m.RightJoinAlias(), m.Left.ForeignKeys, 1)
+}
+
+// RightWhere returns JoinTable's Right foreign key columns, aliased for
+// the query that also selects Left.Other (see LeftJoinAlias), matched
+// against placeholder parameters bound to Right.Other's primary key
+// values.
+func (m ManyToMany) RightWhere() string {
+	return m.sideWhere(m.LeftJoinAlias(), m.Right.ForeignKeys, 1)
+}
+
+// RemoveWhere returns the where clause matching a join-table row by
+// both its Left and Right foreign key columns at once, with
+// placeholders numbered Left columns first then Right. The statement
+// it's used in touches only JoinTable, so the columns are left
+// unqualified.
+func (m ManyToMany) RemoveWhere() string {
+	left := m.sideWhere("", m.Left.ForeignKeys, 1)
+	right := m.sideWhere("", m.Right.ForeignKeys, len(m.Left.ForeignKeys)+1)
+	return left + " and " + right
+}
+
+// sideWhere matches cols against placeholders numbered from startIdx,
+// qualifying each column with alias unless alias is empty.
+func (m ManyToMany) sideWhere(alias string, cols []string, startIdx int) string {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+	r := []string{}
+	idx := startIdx
+	for _, c := range cols {
+		r = append(r, fmt.Sprintf("%s%s=%s", prefix, quoteIdent(c), CurrentDialect.Placeholder(idx)))
+		idx++
+	}
+	return strings.Join(r, " and ")
+}
+
+// LeftJoinOn returns the join condition linking JoinTable's Left foreign
+// key columns to the Left endpoint's primary key columns.
+func (m ManyToMany) LeftJoinOn() string {
+	return m.joinOn(m.LeftJoinAlias(), m.Left)
+}
+
+// RightJoinOn returns the join condition linking JoinTable's Right
+// foreign key columns to the Right endpoint's primary key columns.
+func (m ManyToMany) RightJoinOn() string {
+	return m.joinOn(m.RightJoinAlias(), m.Right)
+}
+
+func (m ManyToMany) joinOn(alias string, jv JoinVars) string {
+	pk := []string{}
+	for _, f := range jv.Other.StructFieldsOrder {
+		if f.DBPrimaryKey {
+			pk = append(pk, f.DBName)
+		}
+	}
+	r := []string{}
+	for i, basecol := range jv.ForeignKeys {
+		if i >= len(pk) {
+			break
+		}
+		r = append(r, fmt.Sprintf("%s.%s=%s.%s", alias, quoteIdent(basecol), jv.Other.V, quoteIdent(pk[i])))
+	}
+	return strings.Join(r, " and ")
+}
+
+////////
+
+// QuotedTableRef returns TableRef ("schema.table") with each part quoted
+// as an identifier for CurrentDialect, so reserved-word schema/table
+// names still produce valid SQL.
+func (s StructVars) QuotedTableRef() string {
+	parts := strings.SplitN(s.TableRef, ".", 2)
+	for i, p := range parts {
+		parts[i] = quoteIdent(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Cols returns a comma-separated string of the database columns
+func (s StructVars) Cols() string {
+	r := []string{}
+	for _, f := range s.StructFields {
+		r = append(r, quoteIdent(f.DBName))
+	}
+	return strings.Join(r, ", ")
+}
+
+// AliasedCols is Cols qualified with this table's own alias V, for use
+// in a multi-table join query where an unqualified column name could be
+// ambiguous across the joined tables (eg. both sides having an "id" PK).
+func (s StructVars) AliasedCols() string {
+	r := []string{}
+	for _, f := range s.StructFields {
+		r = append(r, fmt.Sprintf("%s.%s", s.V, quoteIdent(f.DBName)))
+	}
+	return strings.Join(r, ", ")
+}
+
+// Fields returns a comma-separated string of the struct fields
+func (s StructVars) Fields() string {
+	r := []string{}
+	for _, f := range s.StructFields {
+		r = append(r, s.V+"."+f.GoName)
+	}
+	return strings.Join(r, ", ")
+}
+
+// WherePK returns the where clause for this PK
+func (s StructVars) WherePK() string {
+	pki := 1
+	r := []string{}
+	for _, f := range s.StructFields {
+		if f.DBPrimaryKey {
+			r = append(r, fmt.Sprintf("%s=%s", quoteIdent(f.DBName), CurrentDialect.Placeholder(pki)))
+			pki += 1
+		}
+	}
+	return strings.Join(r, " and ")
+}
+
+// FieldsPK returns the struct Fields for the PK
+func (s StructVars) FieldsPK() string {
+	r := []string{}
+	for _, f := range s.StructFields {
+		if f.DBPrimaryKey {
+			r = append(r, s.V+"."+f.GoName)
+		}
+	}
+	return strings.Join(r, ", ")
+}
+
+// WhereFK returns the where clause for the FK
+func (j JoinVars) WhereFK() string {
+	idx := 1
+	r := []string{}
+	for _, f := range j.Base.StructFields {
+		for _, k := range j.ForeignKeys {
+			if k == f.DBName {
+				r = append(r, fmt.Sprintf("%s=%s", quoteIdent(f.DBName), CurrentDialect.Placeholder(idx)))
+				idx += 1
+				break
+			}
+		}
+	}
+	return strings.Join(r, ", ")
+}
+
+// FieldsFK returns the struct Fields for the FK
+func (j JoinVars) FieldsFK() string {
+	r := []string{}
+	for _, f := range j.Base.StructFields {
+		for _, k := range j.ForeignKeys {
+			if k == f.DBName {
+				r = append(r, j.Base.V+"."+f.GoName)
+				break
+			}
+		}
+	}
+	return strings.Join(r, ", ")
+}
+
+// VarsTypesPK returns variable names and Go Types for the PK
+func (s StructVars) VarsTypesPK() string {
+	r := []string{}
+	for _, f := range s.StructFields {
+		if f.DBPrimaryKey {
+			r = append(r, f.DBName+" "+f.GoType)
+		}
+	}
+	return strings.Join(r, ", ")
+}
+
+// VarsPK returns variable names for the PK
+func (s StructVars) VarsPK() string {
+	r := []string{}
+	for _, f := range s.StructFields {
+		if f.DBPrimaryKey {
+			r = append(r, f.DBName)
+		}
+	}
+	return strings.Join(r, ", ")
+}
+
+// UpdateCols returns a comma-separated string of the database columns
+func (s StructVars) UpdateCols() string {
+	idx := 1
+	for _, f := range s.StructFields {
+		if f.DBPrimaryKey {
+			idx += 1
+		}
+	}
+
+	r := []string{}
+	for _, f := range s.StructFields {
+		r = append(r, fmt.Sprintf("%s=%s", quoteIdent(f.DBName), CurrentDialect.Placeholder(idx)))
+		idx += 1
+	}
+	return strings.Join(r, ", ")
+}
+
+// UpdateFields returns a comma-separated string of the struct fields
+func (s StructVars) UpdateFields() string {
+	r := []string{}
+	for _, f := range s.StructFields {
+		r = append(r, s.V+"."+f.GoName)
+	}
+	return strings.Join(r, ", ")
+}
+
+// InsertPlaceholders returns CurrentDialect's placeholders, with DEFAULT
+// for autoincs
+func (s StructVars) InsertPlaceholders() string {
+	idx := 1
+	r := []string{}
+	for _, f := range s.StructFields {
+		if f.DBAutoInc {
+			r = append(r, "DEFAULT")
+		} else {
+			r = append(r, CurrentDialect.Placeholder(idx))
+		}
+		idx += 1
+	}
+	return strings.Join(r, ",")
+}
+
+// SQLXTag returns the `db:"..."` struct tag used by github.com/jmoiron/sqlx
+// to map this column onto a struct field.
+func (f StructField) SQLXTag() string {
+	return fmt.Sprintf("`db:\"%s\"`", f.DBName)
+}
+
+// Tags returns the combined `db:"..." json:"..."` struct tag used by
+// the rest backend, whose structs need both sqlx scanning and JSON
+// encoding on the same fields.
+func (f StructField) Tags() string {
+	return fmt.Sprintf("`db:\"%s\" json:\"%s\"`", f.DBName, f.DBName)
+}
+
+// NamedCols returns the :colname placeholders sqlx's NamedExec/NamedQuery
+// expect, skipping auto-incrementing columns so inserts let the database
+// assign them. It follows StructFieldsOrder, so it lines up with
+// InsertCols.
+func (s StructVars) NamedCols() string {
+	r := []string{}
+	for _, f := range s.StructFieldsOrder {
+		if f.DBAutoInc {
+			continue
+		}
+		r = append(r, ":"+f.DBName)
+	}
+	return strings.Join(r, ", ")
+}
+
+// NamedWherePK returns the where clause for the PK using :colname sqlx
+// named placeholders instead of CurrentDialect's positional ones.
+func (s StructVars) NamedWherePK() string {
+	r := []string{}
+	for _, f := range s.StructFields {
+		if f.DBPrimaryKey {
+			r = append(r, fmt.Sprintf("%s=:%s", quoteIdent(f.DBName), f.DBName))
+		}
+	}
+	return strings.Join(r, " and ")
+}
+
+// NamedUpdateCols returns the set clause using :colname sqlx named
+// placeholders instead of CurrentDialect's positional ones.
+func (s StructVars) NamedUpdateCols() string {
+	r := []string{}
+	for _, f := range s.StructFields {
+		if f.DBPrimaryKey {
+			continue
+		}
+		r = append(r, fmt.Sprintf("%s=:%s", quoteIdent(f.DBName), f.DBName))
+	}
+	return strings.Join(r, ", ")
+}
+
+// InsertCols returns the database columns to insert, skipping
+// auto-incrementing columns so inserts let the database assign them. It
+// follows StructFieldsOrder, so it lines up with NamedCols.
+func (s StructVars) InsertCols() string {
+	r := []string{}
+	for _, f := range s.StructFieldsOrder {
+		if f.DBAutoInc {
+			continue
+		}
+		r = append(r, quoteIdent(f.DBName))
+	}
+	return strings.Join(r, ", ")
+}
+
+func (f StructField) ParseCode(deststruct string) (string, error) {
+	tname := strings.Replace(f.GoType, ".", "_", -1)
+	if tname[:1] == "*" {
+		tname = tname[1:] + "_ptr"
+	}
+	tpl, err := template.ParseFiles("tpl/special.tpl")
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	tpl.ExecuteTemplate(buf, tname, &struct{ Dest, Src string }{Dest: deststruct + "." + f.GoName, Src: f.ScanName})
+	return buf.String(), nil
+}